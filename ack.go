@@ -0,0 +1,111 @@
+package socketio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/somprabhsharma/go-socket.io/parser"
+)
+
+// AckTimeoutError is returned by EmitWithAck (and passed to
+// EmitWithAckCallback) when the context passed in is done before the
+// server's acknowledgement arrives.
+type AckTimeoutError struct {
+	ID    uint64
+	Event string
+}
+
+func (e *AckTimeoutError) Error() string {
+	return fmt.Sprintf("socketio: timed out waiting for ack of event %q (id %d)", e.Event, e.ID)
+}
+
+type ackResult struct {
+	args []interface{}
+	err  error
+}
+
+// EmitWithAck emits event on the client's namespace connection and blocks
+// until the server acknowledges it or ctx is done, mirroring the v4 JS
+// client's socket.emitWithAck.
+func (c *Client) EmitWithAck(ctx context.Context, event string, args ...interface{}) ([]interface{}, error) {
+	if _, ok := c.conn.namespaces.Get(c.namespace); !ok {
+		return nil, errors.New("socketio: connection namespace not initialized")
+	}
+
+	id := atomic.AddUint64(&c.ackID, 1)
+	result := make(chan ackResult, 1)
+
+	c.lock.Lock()
+	if c.pendingAcks == nil {
+		c.pendingAcks = make(map[uint64]chan ackResult)
+	}
+	c.pendingAcks[id] = result
+	c.lock.Unlock()
+
+	header := parser.Header{
+		Type:      parser.Event,
+		Namespace: c.namespace,
+		ID:        id,
+		NeedAck:   true,
+	}
+
+	c.writeLock.Lock()
+	err := c.conn.encoder.Encode(header, append([]interface{}{event}, args...))
+	c.writeLock.Unlock()
+	if err != nil {
+		c.dropPendingAck(id)
+		return nil, err
+	}
+
+	select {
+	case res := <-result:
+		return res.args, res.err
+	case <-ctx.Done():
+		c.dropPendingAck(id)
+		return nil, &AckTimeoutError{ID: id, Event: event}
+	}
+}
+
+// EmitWithAckCallback is the non-blocking counterpart to EmitWithAck: it
+// emits event and invokes cb with the server's reply once it arrives, or
+// with an *AckTimeoutError once timeout elapses without one.
+func (c *Client) EmitWithAckCallback(event string, timeout time.Duration, cb func([]interface{}, error), args ...interface{}) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		args, err := c.EmitWithAck(ctx, event, args...)
+		cb(args, err)
+	}()
+}
+
+func (c *Client) dropPendingAck(id uint64) {
+	c.lock.Lock()
+	delete(c.pendingAcks, id)
+	c.lock.Unlock()
+}
+
+// clientAckPacketHandler resolves a pending EmitWithAck call when header.ID
+// matches one registered in c.pendingAcks, falling back to the legacy
+// per-call ackPacketHandler for acks tied to inline Emit callbacks.
+func (c *Client) clientAckPacketHandler(header parser.Header) error {
+	c.lock.Lock()
+	result, ok := c.pendingAcks[header.ID]
+	if ok {
+		delete(c.pendingAcks, header.ID)
+	}
+	c.lock.Unlock()
+
+	if !ok {
+		return ackPacketHandler(c.conn, header)
+	}
+
+	var args []interface{}
+	err := c.conn.decoder.DecodeArgs(&args)
+	result <- ackResult{args: args, err: err}
+
+	return err
+}
@@ -0,0 +1,32 @@
+package socketio
+
+// Broker lets a server instance fan broadcasts out to other server
+// instances in a cluster, so that Emit and room broadcasts reach clients
+// connected to any node rather than just the node that received the
+// original event. Implementations are expected to be safe for concurrent
+// use.
+type Broker interface {
+	// Publish sends event with payload to every other node subscribed to
+	// namespace/room. payload is the already-encoded packet body, so
+	// implementations do not need to know about the parser.
+	Publish(namespace, room, event string, payload []byte) error
+
+	// Subscribe registers fn to be called whenever another node publishes
+	// to this broker. Subscribe returns once the subscription is
+	// established; fn is called from a broker-managed goroutine for the
+	// lifetime of the broker.
+	Subscribe(fn func(namespace, room, event string, payload []byte)) error
+}
+
+// RoomTracker is an optional Broker capability for brokers that keep
+// cluster-wide room membership in a shared keyspace, like the Redis
+// broker's sticky room adapter. Server type-asserts its Broker against
+// this interface and silently skips Join/Leave notification for brokers,
+// like the NATS one, that don't implement it.
+type RoomTracker interface {
+	// Join records member as present in room on namespace.
+	Join(namespace, room, member string) error
+
+	// Leave removes member from room on namespace.
+	Leave(namespace, room, member string) error
+}
@@ -0,0 +1,81 @@
+// Package nats implements a socketio.Broker backed by NATS: one subject
+// per namespace. Every server instance subscribes independently (not
+// through a shared queue group), because a cross-node broadcast needs
+// every node - and therefore every node's own local room members - to
+// receive the message; a queue group would deliver it to only one
+// randomly chosen node and silently drop it everywhere else.
+package nats
+
+import (
+	"encoding/json"
+
+	"github.com/nats-io/nats.go"
+)
+
+const subjectPrefix = "socket.io."
+
+type message struct {
+	Room    string `json:"room"`
+	Event   string `json:"event"`
+	Payload []byte `json:"payload"`
+}
+
+// Broker is a socketio.Broker backed by a NATS connection. The zero value
+// is not usable; construct one with New.
+type Broker struct {
+	conn *nats.Conn
+	subs []*nats.Subscription
+}
+
+// New returns a Broker that publishes and subscribes through conn.
+func New(conn *nats.Conn) *Broker {
+	return &Broker{conn: conn}
+}
+
+func subjectFor(namespace string) string {
+	return subjectPrefix + namespace
+}
+
+// Publish broadcasts event/payload to room on namespace.
+func (b *Broker) Publish(namespace, room, event string, payload []byte) error {
+	data, err := json.Marshal(message{Room: room, Event: event, Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	return b.conn.Publish(subjectFor(namespace), data)
+}
+
+// Subscribe subscribes to every namespace subject and dispatches incoming
+// messages to fn. Every Broker in the cluster gets its own subscription so
+// every node sees every published message.
+func (b *Broker) Subscribe(fn func(namespace, room, event string, payload []byte)) error {
+	sub, err := b.conn.Subscribe(subjectPrefix+">", func(msg *nats.Msg) {
+		namespace := msg.Subject[len(subjectPrefix):]
+
+		var m message
+		if err := json.Unmarshal(msg.Data, &m); err != nil {
+			return
+		}
+
+		fn(namespace, m.Room, m.Event, m.Payload)
+	})
+	if err != nil {
+		return err
+	}
+
+	b.subs = append(b.subs, sub)
+
+	return nil
+}
+
+// Close drains every subscription registered through Subscribe.
+func (b *Broker) Close() error {
+	for _, sub := range b.subs {
+		if err := sub.Drain(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package redis implements a socketio.Broker backed by Redis Pub/Sub,
+// modeled after the Node.js socket.io-redis-adapter: one channel per
+// namespace, with room membership tracked in a shared keyspace via an
+// explicit Join/Leave pair so queries like AllRooms/RoomLen can be
+// answered cluster-wide instead of only for the local node.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	channelPrefix = "socket.io#"
+	roomKeyPrefix = "socket.io-rooms#"
+)
+
+// message is the wire format published on a namespace's channel.
+type message struct {
+	Room    string `json:"room"`
+	Event   string `json:"event"`
+	Payload []byte `json:"payload"`
+}
+
+// Broker is a socketio.Broker backed by Redis Pub/Sub. The zero value is
+// not usable; construct one with New.
+type Broker struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+// New returns a Broker that publishes and subscribes through client.
+func New(client *redis.Client) *Broker {
+	return &Broker{
+		client: client,
+		ctx:    context.Background(),
+	}
+}
+
+func channelFor(namespace string) string {
+	return channelPrefix + namespace
+}
+
+func roomKeyFor(namespace, room string) string {
+	return fmt.Sprintf("%s%s#%s", roomKeyPrefix, namespace, room)
+}
+
+func roomKeyPattern(namespace string) string {
+	return fmt.Sprintf("%s%s#*", roomKeyPrefix, namespace)
+}
+
+// Publish broadcasts event/payload to room on namespace to every other
+// node in the cluster.
+func (b *Broker) Publish(namespace, room, event string, payload []byte) error {
+	data, err := json.Marshal(message{Room: room, Event: event, Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	return b.client.Publish(b.ctx, channelFor(namespace), data).Err()
+}
+
+// Subscribe subscribes to every namespace channel, dispatching incoming
+// messages to fn. Subscribe listens on a pattern subscription so it picks
+// up namespaces this broker hasn't published to yet.
+func (b *Broker) Subscribe(fn func(namespace, room, event string, payload []byte)) error {
+	pubsub := b.client.PSubscribe(b.ctx, channelPrefix+"*")
+	if _, err := pubsub.Receive(b.ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			namespace := msg.Channel[len(channelPrefix):]
+
+			var m message
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+				continue
+			}
+
+			fn(namespace, m.Room, m.Event, m.Payload)
+		}
+	}()
+
+	return nil
+}
+
+// Join records member as present in room on namespace, in the shared
+// keyspace every node in the cluster reads from. member should uniquely
+// identify the connection (its socket id), not the node.
+func (b *Broker) Join(namespace, room, member string) error {
+	return b.client.SAdd(b.ctx, roomKeyFor(namespace, room), member).Err()
+}
+
+// Leave removes member from room on namespace. Once a room's last member
+// leaves, the underlying key is deleted so it no longer shows up in
+// AllRooms.
+func (b *Broker) Leave(namespace, room, member string) error {
+	key := roomKeyFor(namespace, room)
+	if err := b.client.SRem(b.ctx, key, member).Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RoomLen returns the number of members sharing room on namespace across
+// the whole cluster.
+func (b *Broker) RoomLen(namespace, room string) (int64, error) {
+	return b.client.SCard(b.ctx, roomKeyFor(namespace, room)).Result()
+}
+
+// AllRooms returns the names of every non-empty room on namespace across
+// the whole cluster.
+func (b *Broker) AllRooms(namespace string) ([]string, error) {
+	var rooms []string
+
+	prefix := roomKeyPrefix + namespace + "#"
+	iter := b.client.Scan(b.ctx, 0, roomKeyPattern(namespace), 0).Iterator()
+	for iter.Next(b.ctx) {
+		rooms = append(rooms, strings.TrimPrefix(iter.Val(), prefix))
+	}
+
+	return rooms, iter.Err()
+}
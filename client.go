@@ -1,17 +1,19 @@
 package socketio
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/somprabhsharma/go-socket.io/engineio"
 	"github.com/somprabhsharma/go-socket.io/engineio/transport"
 	"github.com/somprabhsharma/go-socket.io/engineio/transport/polling"
+	"github.com/somprabhsharma/go-socket.io/engineio/transport/sse"
 	"github.com/somprabhsharma/go-socket.io/engineio/transport/websocket"
 	"github.com/somprabhsharma/go-socket.io/logger"
 	"github.com/somprabhsharma/go-socket.io/parser"
+	"math"
 	"net/url"
 	"path"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -33,9 +35,28 @@ type Client struct {
 
 	reconnection         bool
 	reconnecting         bool
+	reconnectCancel      context.CancelFunc
 	reconnectionDelay    int
 	reconnectionDelayMax int
-	reconnectionAttempts float64
+	reconnectionAttempts int
+	onReconnectAttempt   func(attempt int, err error)
+
+	ackID       uint64
+	pendingAcks map[uint64]chan ackResult
+
+	streamID           uint32
+	streams            sync.Map // uint32 -> *Stream
+	pendingStreamOpens sync.Map // uint32 -> chan struct{}, closed once the peer acks
+
+	// streamOpens delivers Streams the peer opened toward us, for
+	// AcceptStream to hand out.
+	streamOpens chan *Stream
+
+	// writeLock serializes every Encode call issued on c.conn.encoder
+	// outside of clientWrite's writeChan loop (EmitWithAck, Stream
+	// writes/opens/closes), so they can't interleave with clientWrite's
+	// own Encode call or each other.
+	writeLock sync.Mutex
 }
 
 // NewClient returns a server
@@ -58,8 +79,15 @@ func NewClient(addr string, opts *engineio.Options) (*Client, error) {
 	if strings.HasSuffix(u.Path, "socket.io") {
 		u.Path += "/"
 	}
-	// attempts
-	attempts, _ := strconv.ParseFloat("Infinity", 64)
+
+	if opts != nil && opts.ParserFactory != nil {
+		if codec := opts.ParserFactory(); codec != nil {
+			q := u.Query()
+			q.Set("EIO", "4")
+			q.Set("parser", codec.Name())
+			u.RawQuery = q.Encode()
+		}
+	}
 
 	return &Client{
 		namespace: namespace,
@@ -67,15 +95,19 @@ func NewClient(addr string, opts *engineio.Options) (*Client, error) {
 		handlers:  newNamespaceHandlers(),
 		opts:      opts,
 		retryStrategy: NewBackOff(RetryStrategy{
-			ms:       float64(3 * time.Second),
-			max:      float64(10 * time.Second),
+			Policy:   PolicyExponential,
+			ms:       3 * time.Second,
+			max:      10 * time.Second,
 			factor:   2,
 			jitter:   0.5,
 			attempts: 0,
 		}),
-		reconnection:         true,
-		reconnecting:         false,
-		reconnectionAttempts: attempts,
+		streamOpens:  make(chan *Stream, 16),
+		reconnection: true,
+		reconnecting: false,
+		// There is no integer representation of "Infinity", so an
+		// effectively unbounded number of attempts is used instead.
+		reconnectionAttempts: math.MaxInt32,
 	}, err
 }
 
@@ -87,45 +119,84 @@ func fmtNS(ns string) string {
 	return ns
 }
 
+// ReConnection starts the reconnect loop using a background context. Use
+// Close to abort a pending attempt.
 func (c *Client) ReConnection() error {
-	return c.reconnect()
+	return c.reconnect(context.Background())
 }
 
-func (c *Client) reconnect() error {
-	// reconnecting return
+// OnReconnectAttempt registers a callback invoked after every reconnect
+// attempt with the 1-based attempt number and the error returned by
+// Connect, or nil once it succeeds. It allows users to implement their own
+// circuit-breaker behavior on top of the built-in retry loop.
+func (c *Client) OnReconnectAttempt(f func(attempt int, err error)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.onReconnectAttempt = f
+}
+
+func (c *Client) reconnect(ctx context.Context) error {
+	c.lock.Lock()
 	if c.reconnecting {
+		c.lock.Unlock()
 		return nil
 	}
-	if c.retryStrategy.attempts >= c.reconnectionAttempts {
-		c.retryStrategy.Reset()
-		c.reconnecting = false
-		logger.Info("reconnect failed: reconnect times more than reconnect attempts")
-		return errors.New("reconnect failed: reconnect times more than reconnect attempts")
-	}
-	// Duration delay
-	delay := c.retryStrategy.Duration()
+
+	ctx, cancel := context.WithCancel(ctx)
 	c.reconnecting = true
+	c.reconnectCancel = cancel
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		c.reconnecting = false
+		c.reconnectCancel = nil
+		c.lock.Unlock()
+		cancel()
+	}()
+
+	attempt := 0
 	for {
-		logger.Info(fmt.Sprintf("client will wait some %dms before reconnect attempt", time.Duration(delay)/time.Millisecond))
-		time.Sleep(time.Duration(delay))
-		// reconnect
+		if attempt >= c.reconnectionAttempts {
+			c.retryStrategy.Reset()
+			logger.Info("reconnect failed: reconnect times more than reconnect attempts")
+			return errors.New("reconnect failed: reconnect times more than reconnect attempts")
+		}
+
+		delay := c.retryStrategy.NextBackoff()
+		logger.Info(fmt.Sprintf("client will wait some %s before reconnect attempt", delay))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info("reconnect canceled")
+			return ctx.Err()
+		case <-timer.C:
+		}
+
 		err := c.Connect()
+		attempt++
+
+		c.lock.Lock()
+		onReconnectAttempt := c.onReconnectAttempt
+		c.lock.Unlock()
+		if onReconnectAttempt != nil {
+			onReconnectAttempt(attempt, err)
+		}
+
 		if err == nil {
-			// reset
 			c.retryStrategy.Reset()
-			c.reconnecting = false
-			break
+			return nil
 		}
 		logger.Error("reconnect failed: ", err)
-		// reset
-		c.reconnecting = false
 	}
-	return nil
 }
 
 func (c *Client) Connect() error {
 	dialer := engineio.Dialer{
-		Transports: []transport.Transport{polling.Default, websocket.Default},
+		Transports: []transport.Transport{polling.Default, sse.Default, websocket.Default},
 	}
 	// Use opts Transports when NewClient
 	if c.opts != nil && len(c.opts.Transports) > 0 {
@@ -139,6 +210,10 @@ func (c *Client) Connect() error {
 
 	c.conn = newConn(enginioCon, c.handlers)
 
+	if c.opts != nil {
+		installParserFactory(c.conn, enginioCon, c.opts.ParserFactory)
+	}
+
 	if err := c.conn.connectClient(); err != nil {
 		_ = c.Close()
 		if root, ok := c.handlers.Get(rootNamespace); ok && root.onError != nil {
@@ -155,16 +230,55 @@ func (c *Client) Connect() error {
 	return nil
 }
 
-// Close closes server.
+// Close closes the connection and permanently disables reconnection for
+// this Client; a Client closed this way is not meant to be reused.
 func (c *Client) Close() error {
-	if c.reconnection {
-		c.retryStrategy.Reset()
-		c.reconnecting = false
-		return c.reconnect()
+	c.lock.Lock()
+	// Disabling reconnection first means a reconnect attempt racing this
+	// Close, once it observes the cancellation below, won't loop back
+	// around and start another attempt.
+	c.reconnection = false
+	if c.reconnectCancel != nil {
+		c.reconnectCancel()
+	}
+	c.lock.Unlock()
+
+	return c.closeConn()
+}
+
+// closeConn closes the current transport connection without touching
+// c.reconnection, unlike Close. clientError/clientWrite/clientRead call
+// this when they observe the transport has died on its own, so releasing
+// it doesn't also cancel the automatic reconnect they're about to start.
+func (c *Client) closeConn() error {
+	if c.conn == nil {
+		return nil
 	}
+
 	return c.conn.Close()
 }
 
+// maybeReconnect starts a reconnect attempt if reconnection is still
+// enabled, i.e. Close hasn't been called. Used both by the Disconnect
+// packet handler and by the read/write/error goroutines when the
+// transport drops out from under them.
+func (c *Client) maybeReconnect() {
+	c.lock.Lock()
+	reconnection := c.reconnection
+	c.lock.Unlock()
+	if !reconnection {
+		return
+	}
+
+	if err := c.reconnect(context.Background()); err != nil {
+		// context.Canceled means Close ran concurrently and stopped
+		// this reconnect on purpose; that is not a failure.
+		if !errors.Is(err, context.Canceled) {
+			logger.Error("reconnect failed:", err)
+		}
+	}
+}
+
 func (c *Client) Emit(event string, args ...interface{}) {
 	nsConn, ok := c.conn.namespaces.Get(c.namespace)
 	if !ok {
@@ -194,12 +308,8 @@ func (c *Client) OnDisconnect(f func(Conn, string)) {
 
 	h.OnDisconnect(func(cc Conn, s string) {
 		f(cc, s)
-		if c.reconnection {
-			err := c.reconnect()
-			if err != nil {
-				panic(err)
-			}
-		}
+
+		c.maybeReconnect()
 	})
 
 }
@@ -226,9 +336,11 @@ func (c *Client) OnEvent(event string, f interface{}) {
 
 func (c *Client) clientError() {
 	defer func() {
-		if err := c.Close(); err != nil {
+		if err := c.closeConn(); err != nil {
 			logger.Error("close connect:", err)
 		}
+
+		c.maybeReconnect()
 	}()
 
 	for {
@@ -258,10 +370,11 @@ func (c *Client) clientError() {
 
 func (c *Client) clientWrite() {
 	defer func() {
-		if err := c.Close(); err != nil {
+		if err := c.closeConn(); err != nil {
 			logger.Error("close connect:", err)
 		}
 
+		c.maybeReconnect()
 	}()
 
 	for {
@@ -270,7 +383,10 @@ func (c *Client) clientWrite() {
 			logger.Info("clientWrite Writer loop has stopped")
 			return
 		case pkg := <-c.conn.writeChan:
-			if err := c.conn.encoder.Encode(pkg.Header, pkg.Data); err != nil {
+			c.writeLock.Lock()
+			err := c.conn.encoder.Encode(pkg.Header, pkg.Data)
+			c.writeLock.Unlock()
+			if err != nil {
 				c.conn.onError(pkg.Header.Namespace, err)
 			}
 		}
@@ -279,9 +395,11 @@ func (c *Client) clientWrite() {
 
 func (c *Client) clientRead() {
 	defer func() {
-		if err := c.Close(); err != nil {
+		if err := c.closeConn(); err != nil {
 			logger.Error("close connect:", err)
 		}
+
+		c.maybeReconnect()
 	}()
 
 	var event string
@@ -304,7 +422,7 @@ func (c *Client) clientRead() {
 		var err error
 		switch header.Type {
 		case parser.Ack:
-			err = ackPacketHandler(c.conn, header)
+			err = c.clientAckPacketHandler(header)
 		case parser.Connect:
 			err = clientConnectPacketHandler(c.conn, header)
 		case parser.Disconnect:
@@ -312,7 +430,10 @@ func (c *Client) clientRead() {
 		case parser.Event:
 			err = eventPacketHandler(c.conn, event, header)
 		default:
-
+			switch header.Type {
+			case parser.StreamOpen, parser.StreamData, parser.StreamClose:
+				err = c.handleStreamPacket(header)
+			}
 		}
 
 		if err != nil {
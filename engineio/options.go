@@ -0,0 +1,18 @@
+package engineio
+
+import (
+	"github.com/somprabhsharma/go-socket.io/engineio/transport"
+	"github.com/somprabhsharma/go-socket.io/parser"
+)
+
+// Options configures a client or server engine.io connection.
+type Options struct {
+	// Transports lists the transports available for negotiation, in
+	// priority order.
+	Transports []transport.Transport
+
+	// ParserFactory builds the parser.Codec used to encode and decode
+	// packets on the connection. When nil, the default JSON codec is
+	// used, matching the historical behavior of this package.
+	ParserFactory func() parser.Codec
+}
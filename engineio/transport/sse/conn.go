@@ -0,0 +1,325 @@
+package sse
+
+import (
+	"bytes"
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/somprabhsharma/go-socket.io/engineio/frame"
+)
+
+const defaultPingTimeout = 60 * time.Second
+
+// conn implements transport.Conn on top of a text/event-stream response for
+// reads and plain HTTP POST bodies for writes, reusing the polling
+// encoder's body format for the POST side.
+type conn struct {
+	remote string // the server URL (client side) or request URL (server side)
+	sid    string
+
+	httpClient *http.Client
+	header     http.Header
+
+	lock    sync.Mutex
+	closed  chan struct{}
+	closeOn sync.Once
+
+	enc *encoder
+	dec *decoder
+
+	// posts carries frames delivered by client POST requests on a
+	// server-side conn. Unlike the client side, which decodes a single
+	// continuous response body through dec, the server side receives
+	// each client->server frame on its own HTTP request, so there is no
+	// stream for a decoder to read from; NextReader drains this instead.
+	posts chan postedFrame
+}
+
+// postedFrame is one client->server frame recovered from an HTTP POST
+// body on a server-side conn.
+type postedFrame struct {
+	payload  []byte
+	isBinary bool
+}
+
+func (c *conn) NextWriter(ft frame.Type) (io.WriteCloser, error) {
+	// The server side streams frames out over the open SSE response; the
+	// client side has no such response to write to; it POSTs instead.
+	if c.enc != nil {
+		return c.enc.NextFrame(ft)
+	}
+	if c.httpClient != nil {
+		return &postWriter{conn: c, binary: ft == frame.Binary}, nil
+	}
+
+	return nil, fmt.Errorf("sse: connection has no writer")
+}
+
+// postWriter buffers one frame's bytes and, on Close, POSTs them to the
+// server as a single polling-style body: plain bytes for a text frame, or
+// the binaryPrefix-tagged base64 body the polling encoder uses for a
+// binary frame, which the server side's decodePostBody reads back.
+type postWriter struct {
+	conn   *conn
+	buf    bytes.Buffer
+	binary bool
+}
+
+func (w *postWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *postWriter) Close() error {
+	body := w.buf.Bytes()
+	if w.binary {
+		encoded := make([]byte, base64.StdEncoding.EncodedLen(len(body))+1)
+		encoded[0] = binaryPrefix
+		base64.StdEncoding.Encode(encoded[1:], body)
+		body = encoded
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.conn.remote, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = w.conn.header.Clone()
+
+	resp, err := w.conn.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sse: unexpected POST status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *conn) NextReader() (frame.Type, io.ReadCloser, error) {
+	if c.dec != nil {
+		payload, isBinary, err := c.dec.NextPayload()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		ft := frame.String
+		if isBinary {
+			ft = frame.Binary
+		}
+
+		return ft, ioutil.NopCloser(bytes.NewReader(payload)), nil
+	}
+
+	select {
+	case p, ok := <-c.posts:
+		if !ok {
+			return 0, nil, io.EOF
+		}
+
+		ft := frame.String
+		if p.isBinary {
+			ft = frame.Binary
+		}
+
+		return ft, ioutil.NopCloser(bytes.NewReader(p.payload)), nil
+	case <-c.closed:
+		return 0, nil, io.EOF
+	}
+}
+
+// acceptPost decodes the body of a client POST request - the
+// client->server leg, reusing the polling encoder's wire format the same
+// way postWriter.Close() writes it - and queues the resulting frame for
+// the next NextReader call.
+func (c *conn) acceptPost(r *http.Request) error {
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	payload, isBinary, err := decodePostBody(body)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case c.posts <- postedFrame{payload: payload, isBinary: isBinary}:
+		return nil
+	case <-c.closed:
+		return fmt.Errorf("sse: connection closed")
+	}
+}
+
+// decodePostBody reverses postWriter.Close()'s encoding: a leading
+// binaryPrefix byte marks base64-encoded binary data, otherwise the body
+// is the frame's bytes as-is.
+func decodePostBody(body []byte) (payload []byte, isBinary bool, err error) {
+	if len(body) == 0 {
+		return nil, false, nil
+	}
+
+	if body[0] == binaryPrefix {
+		decoded, err := base64.StdEncoding.DecodeString(string(body[1:]))
+		if err != nil {
+			return nil, false, err
+		}
+
+		return decoded, true, nil
+	}
+
+	return body, false, nil
+}
+
+func (c *conn) Close() error {
+	c.closeOn.Do(func() {
+		if c.sid != "" {
+			sessions.Delete(c.sid)
+		}
+		close(c.closed)
+	})
+
+	return nil
+}
+
+// LastEventID returns the id of the last event this connection decoded, or
+// "" if it is a server-side connection or nothing has been decoded yet.
+func (c *conn) LastEventID() string {
+	if c.dec == nil {
+		return ""
+	}
+
+	return c.dec.LastEventID()
+}
+
+// Reconnect dials the same remote again, sending the Last-Event-ID this
+// connection last saw as a request header. newServerConn does not read
+// that header and does not replay missed events: the new server-side conn
+// numbers its events from 1 again. Callers that reconnect a dropped
+// client-side connection should use this instead of calling dial directly.
+func (c *conn) Reconnect(ctx context.Context) (*conn, error) {
+	u, err := url.Parse(c.remote)
+	if err != nil {
+		return nil, err
+	}
+
+	header := c.header.Clone()
+	if id := c.LastEventID(); id != "" {
+		header.Set("Last-Event-ID", id)
+	}
+
+	return dial(ctx, u, header)
+}
+
+// newServerConn accepts an incoming SSE request, keeping the response
+// writer open for the lifetime of the connection and streaming encoded
+// frames to it as they are written. The Last-Event-ID header is not read;
+// a resumed connection is recognised only by the sid query parameter.
+//
+// The sid query parameter, if present, is an existing session this GET is
+// resuming; otherwise a new sid is minted and the conn registered under it
+// so the client's subsequent POST requests - carrying the client->server
+// leg, on their own separate HTTP requests - can be routed back to it by
+// sseTransport.Accept.
+func newServerConn(w http.ResponseWriter, r *http.Request) (*conn, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("sse: ResponseWriter does not support flushing")
+	}
+
+	sid := r.URL.Query().Get("sid")
+	if sid == "" {
+		var err error
+		sid, err = newSessionID()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	closed := make(chan struct{})
+
+	c := &conn{
+		remote: r.URL.String(),
+		sid:    sid,
+		closed: closed,
+		enc:    newEncoder(defaultPingTimeout, closed, w),
+		posts:  make(chan postedFrame, 8),
+	}
+	sessions.Store(sid, c)
+
+	go func() {
+		for {
+			if err := c.enc.WriteFramesTo(func() error {
+				flusher.Flush()
+				return nil
+			}); err != nil {
+				_ = c.Close()
+				return
+			}
+		}
+	}()
+
+	return c, nil
+}
+
+// newSessionID returns a random hex string identifying a new server-side
+// session, for newServerConn to register its conn under.
+func newSessionID() (string, error) {
+	var b [16]byte
+	if _, err := cryptorand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b[:]), nil
+}
+
+// dial connects to an engine.io server's SSE endpoint for the
+// server->client leg. Client->server writes are sent as individual HTTP
+// POST requests carrying the polling-compatible body, matching how the
+// polling transport's client side behaves.
+func dial(ctx context.Context, u *url.URL, requestHeader http.Header) (*conn, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = requestHeader.Clone()
+	req.Header.Set("Accept", "text/event-stream")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("sse: unexpected status %d", resp.StatusCode)
+	}
+
+	closed := make(chan struct{})
+
+	c := &conn{
+		remote:     u.String(),
+		httpClient: client,
+		header:     requestHeader,
+		closed:     closed,
+		dec:        newDecoder(resp.Body),
+	}
+
+	return c, nil
+}
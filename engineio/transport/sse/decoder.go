@@ -0,0 +1,78 @@
+package sse
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"strings"
+)
+
+// decoder parses a text/event-stream body into the raw engine.io frame
+// payloads it carries. Each event becomes one "data:" line (chunked writes
+// from the encoder are always flushed as a whole event), so the decoder
+// only needs to track the last seen id for reconnection, not multi-line
+// "data:" accumulation.
+type decoder struct {
+	scanner   *bufio.Scanner
+	lastEvent string
+}
+
+// maxLineSize caps a single "data:" line, which carries one whole engine.io
+// frame (base64-encoded if binary). bufio.Scanner's default ~64KB limit is too small
+// for frames like stream/file-upload chunks, so it's raised here and the
+// scanner is allowed to grow its buffer up to it.
+const maxLineSize = 10 * 1024 * 1024
+
+func newDecoder(r io.Reader) *decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	return &decoder{
+		scanner: scanner,
+	}
+}
+
+// LastEventID returns the id of the most recently decoded event, suitable
+// for sending back as the Last-Event-ID header when reconnecting.
+func (d *decoder) LastEventID() string {
+	return d.lastEvent
+}
+
+// NextPayload returns the next frame payload from the stream, decoding it
+// from base64 if it was marked as binary with the shared binaryPrefix.
+func (d *decoder) NextPayload() (payload []byte, isBinary bool, err error) {
+	for d.scanner.Scan() {
+		line := d.scanner.Text()
+
+		switch {
+		case line == "":
+			// blank line: event boundary with nothing pending, ignore.
+			continue
+		case strings.HasPrefix(line, "id:"):
+			d.lastEvent = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+			continue
+		case strings.HasPrefix(line, ":"):
+			// comment, used as a keep-alive; ignore.
+			continue
+		case strings.HasPrefix(line, "data:"):
+			data := strings.TrimPrefix(line, "data:")
+			data = strings.TrimPrefix(data, " ")
+
+			if strings.HasPrefix(data, string(binaryPrefix)) {
+				decoded, err := base64.StdEncoding.DecodeString(data[1:])
+				if err != nil {
+					return nil, false, err
+				}
+				return decoded, true, nil
+			}
+
+			return []byte(data), false, nil
+		}
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return nil, false, err
+	}
+
+	return nil, false, io.EOF
+}
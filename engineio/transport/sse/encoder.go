@@ -0,0 +1,151 @@
+package sse
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/somprabhsharma/go-socket.io/engineio/frame"
+)
+
+// ErrPingTimeout is returned by WriteFramesTo when no frame became
+// available before pingTimeout elapsed, mirroring
+// polling.encoder.WriteFramesTo.
+var ErrPingTimeout = errors.New("ping timeout")
+
+// binaryPrefix marks a base64-encoded binary frame, matching the prefix
+// byte used by the polling transport so the two encodings stay compatible
+// on the wire.
+const binaryPrefix = 'b'
+
+// encoder writes engine.io packets as Server-Sent Events: every frame
+// becomes one "data: ..." line followed by a blank line. SSE forbids
+// newlines inside a single "data:" field, so binary frames are always
+// base64-encoded rather than written with the embedded separator byte used
+// by the polling encoder.
+type encoder struct {
+	pingTimeout       time.Duration
+	lastPing          time.Time
+	writer            *bufio.Writer
+	hasFramesChan     chan struct{}
+	closed            chan struct{}
+	hasNonClosedFrame int32
+	nextID            uint64
+}
+
+func newEncoder(pingTimeout time.Duration, closed chan struct{}, w io.Writer) *encoder {
+	return &encoder{
+		pingTimeout:   pingTimeout,
+		lastPing:      time.Now(),
+		writer:        bufio.NewWriter(w),
+		hasFramesChan: make(chan struct{}, 1),
+		closed:        closed,
+	}
+}
+
+func (e *encoder) NextFrame(ft frame.Type) (io.WriteCloser, error) {
+	select {
+	case <-e.closed:
+		return nil, io.EOF
+	default:
+	}
+
+	if !atomic.CompareAndSwapInt32(&e.hasNonClosedFrame, 0, 1) {
+		return nil, errors.New("has a non-closed frame writer")
+	}
+
+	// Every frame gets its own monotonically increasing id line so a
+	// dropped client can resume with Last-Event-ID; see decoder.lastEvent.
+	id := atomic.AddUint64(&e.nextID, 1)
+	if _, err := e.writer.WriteString("id: "); err != nil {
+		return nil, err
+	}
+	if _, err := e.writer.WriteString(strconv.FormatUint(id, 10)); err != nil {
+		return nil, err
+	}
+	if err := e.writer.WriteByte('\n'); err != nil {
+		return nil, err
+	}
+
+	if _, err := e.writer.WriteString("data: "); err != nil {
+		return nil, err
+	}
+
+	ret := &frameWriter{
+		writer:            e.writer,
+		hasFramesChan:     e.hasFramesChan,
+		hasNonClosedFrame: &e.hasNonClosedFrame,
+	}
+
+	if ft == frame.Binary {
+		if err := ret.writer.WriteByte(binaryPrefix); err != nil {
+			return nil, err
+		}
+		ret.base64 = base64.NewEncoder(base64.StdEncoding, ret.writer)
+	}
+
+	return ret, nil
+}
+
+// WriteFramesTo flushes any frame finished since the last call, blocking
+// until one becomes available or pingTimeout elapses, exactly like
+// polling.encoder.WriteFramesTo. Callers are expected to loop this over a
+// single long-lived ResponseWriter.
+func (e *encoder) WriteFramesTo(flush func() error) error {
+	pingTimeout := e.pingTimeout - time.Since(e.lastPing)
+	select {
+	case <-e.hasFramesChan:
+	case <-time.After(pingTimeout):
+		e.lastPing = time.Now()
+		return ErrPingTimeout
+	case <-e.closed:
+		return io.EOF
+	}
+
+	if err := e.writer.Flush(); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+type frameWriter struct {
+	writer            *bufio.Writer
+	base64            io.WriteCloser
+	hasFramesChan     chan struct{}
+	hasNonClosedFrame *int32
+}
+
+func (w *frameWriter) Write(b []byte) (int, error) {
+	if w.base64 != nil {
+		return w.base64.Write(b)
+	}
+
+	return w.writer.Write(b)
+}
+
+func (w *frameWriter) Close() error {
+	if w.base64 != nil {
+		if err := w.base64.Close(); err != nil {
+			return err
+		}
+	}
+
+	// SSE frames end with a blank line.
+	if _, err := w.writer.WriteString("\n\n"); err != nil {
+		return err
+	}
+
+	atomic.StoreInt32(w.hasNonClosedFrame, 0)
+
+	select {
+	case w.hasFramesChan <- struct{}{}:
+	default: // if it already has frames, the chan is full and continues.
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+// Package sse implements an engine.io transport backed by Server-Sent
+// Events (text/event-stream) for server->client delivery, reusing HTTP
+// POST bodies for the client->server leg in the same way the polling
+// transport does. Unlike polling, the server->client leg stays open as a
+// single long-lived response, which makes it work through proxies that
+// strip WebSocket upgrades but still pass through long-lived text streams.
+package sse
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/somprabhsharma/go-socket.io/engineio/transport"
+)
+
+// Name is the transport name negotiated with the "transport" query string
+// parameter, alongside "polling" and "websocket".
+const Name = "sse"
+
+// Default is the default SSE transport. It can be added to
+// engineio.Options.Transports on the server, or engineio.Dialer.Transports
+// on the client, next to polling.Default and websocket.Default.
+var Default transport.Transport = sseTransport{}
+
+// sessions tracks server-side conns by sid. A client's POST request (the
+// client->server leg) arrives on its own HTTP request, separate from the
+// long-lived GET that opened the SSE stream, so Accept needs a way to
+// route it back to the conn that GET created.
+var sessions sync.Map // sid string -> *conn
+
+type sseTransport struct{}
+
+func (sseTransport) Name() string {
+	return Name
+}
+
+func (t sseTransport) Accept(w http.ResponseWriter, r *http.Request) (transport.Conn, error) {
+	if r.Method == http.MethodPost {
+		return acceptPost(w, r)
+	}
+
+	return newServerConn(w, r)
+}
+
+// acceptPost looks up the session an incoming POST belongs to by its sid
+// query parameter and feeds the request body to that session's conn.
+func acceptPost(w http.ResponseWriter, r *http.Request) (transport.Conn, error) {
+	sid := r.URL.Query().Get("sid")
+
+	v, ok := sessions.Load(sid)
+	if !ok {
+		http.Error(w, "sse: unknown session", http.StatusBadRequest)
+		return nil, fmt.Errorf("sse: no session for sid %q", sid)
+	}
+	c := v.(*conn)
+
+	if err := c.acceptPost(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, err
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	return c, nil
+}
+
+func (t sseTransport) Dial(ctx context.Context, url *url.URL, requestHeader http.Header) (transport.Conn, error) {
+	return dial(ctx, url, requestHeader)
+}
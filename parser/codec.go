@@ -0,0 +1,26 @@
+package parser
+
+import "io"
+
+// Codec builds the Encoder/Decoder pair used to read and write packets on
+// a connection. The package's default JSON wire format is used when none
+// is configured; parser/msgpack provides a smaller, binary-friendly
+// alternative that can be installed through engineio.Options.ParserFactory.
+type Codec interface {
+	// Name identifies the codec for wire negotiation, e.g. "json" or
+	// "msgpack".
+	Name() string
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder writes packets in a codec's wire format.
+type Encoder interface {
+	Encode(header Header, args interface{}) error
+}
+
+// Decoder reads packets in a codec's wire format.
+type Decoder interface {
+	DecodeHeader(header *Header, event *string) error
+	DecodeArgs(v interface{}) error
+}
@@ -0,0 +1,101 @@
+// Package msgpack implements a parser.Codec compatible with the
+// socket.io-msgpack-parser wire format used by the JS client/server: every
+// packet, including any binary attachments, is encoded inline as a single
+// msgpack-encoded binary frame rather than the JSON-plus-separate-binary-
+// attachment framing used by the default parser. This trades some CPU for
+// smaller payloads, which matters most for bandwidth-constrained clients.
+package msgpack
+
+import (
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/somprabhsharma/go-socket.io/parser"
+)
+
+// Name is the codec name negotiated in the connection's query string.
+const Name = "msgpack"
+
+// Codec is a parser.Codec implementing the socket.io v5 msgpack-parser
+// wire format. The zero value is ready to use.
+type Codec struct{}
+
+// Name implements parser.Codec.
+func (Codec) Name() string { return Name }
+
+// NewEncoder implements parser.Codec.
+func (Codec) NewEncoder(w io.Writer) parser.Encoder {
+	return &encoder{enc: msgpack.NewEncoder(w)}
+}
+
+// NewDecoder implements parser.Codec.
+func (Codec) NewDecoder(r io.Reader) parser.Decoder {
+	return &decoder{dec: msgpack.NewDecoder(r)}
+}
+
+// wirePacket is the single msgpack frame carrying both the header and the
+// event payload, mirroring the flat object {type, nsp, id, data} produced
+// by the JS msgpack-parser.
+type wirePacket struct {
+	Type      parser.Type `msgpack:"type"`
+	Namespace string      `msgpack:"nsp"`
+	ID        uint64      `msgpack:"id,omitempty"`
+	NeedAck   bool        `msgpack:"needAck,omitempty"`
+	Data      interface{} `msgpack:"data,omitempty"`
+}
+
+type encoder struct {
+	enc *msgpack.Encoder
+}
+
+func (e *encoder) Encode(header parser.Header, args interface{}) error {
+	return e.enc.Encode(wirePacket{
+		Type:      header.Type,
+		Namespace: header.Namespace,
+		ID:        header.ID,
+		NeedAck:   header.NeedAck,
+		Data:      args,
+	})
+}
+
+type decoder struct {
+	dec     *msgpack.Decoder
+	pending interface{}
+}
+
+func (d *decoder) DecodeHeader(header *parser.Header, event *string) error {
+	var p wirePacket
+	if err := d.dec.Decode(&p); err != nil {
+		return err
+	}
+
+	header.Type = p.Type
+	header.Namespace = p.Namespace
+	header.ID = p.ID
+	header.NeedAck = p.NeedAck
+	d.pending = p.Data
+
+	if args, ok := p.Data.([]interface{}); ok && len(args) > 0 {
+		if name, ok := args[0].(string); ok {
+			*event = name
+		}
+	}
+
+	return nil
+}
+
+func (d *decoder) DecodeArgs(v interface{}) error {
+	// d.pending was itself decoded generically (wirePacket.Data is
+	// interface{}), so it's already a []interface{} for event/ack args but
+	// a map[string]interface{} for a struct like streamPayload. Re-marshal
+	// it through msgpack and decode into v rather than hard-coding one
+	// destination shape, so every caller - not just *[]interface{} - gets
+	// a real decode instead of a silent no-op.
+	data, err := msgpack.Marshal(d.pending)
+	if err != nil {
+		return err
+	}
+
+	return msgpack.Unmarshal(data, v)
+}
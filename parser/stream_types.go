@@ -0,0 +1,20 @@
+package parser
+
+// Stream packet types extend the base engine.io/socket.io packet types to
+// carry multiplexed byte streams over the same connection, alongside
+// ordinary event and ack packets. They are offset well past the handful of
+// base types (Connect, Disconnect, Event, Ack, ...) to avoid colliding
+// with them. No stream capability is negotiated in the Connect packet:
+// they are sent to the peer without first checking that it supports them.
+const (
+	// StreamOpen announces a new Stream with the id carried in the
+	// packet header. Its payload carries only the stream's name; both
+	// peers start from the same fixed initial window.
+	StreamOpen Type = 100 + iota
+	// StreamData carries a chunk of stream payload, optionally
+	// piggy-backing a flow-control window update for the same stream.
+	StreamData
+	// StreamClose tears a stream down in one direction; a stream is
+	// fully closed once both peers have sent one.
+	StreamClose
+)
@@ -0,0 +1,111 @@
+package socketio
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/somprabhsharma/go-socket.io/engineio/frame"
+	"github.com/somprabhsharma/go-socket.io/parser"
+)
+
+// frameConn is the subset of the engineio connection a parser.Codec needs
+// to read and write packets: one engine.io frame per Encode/Decode call.
+type frameConn interface {
+	NextWriter(ft frame.Type) (io.WriteCloser, error)
+	NextReader() (frame.Type, io.ReadCloser, error)
+}
+
+// frameReadWriter adapts a frameConn into the plain io.Writer/io.Reader
+// shape parser.Codec.NewEncoder/NewDecoder expect. Writes are buffered
+// rather than sent immediately, since a codec's Encode call (msgpack in
+// particular) issues many small Writes per packet; Flush sends everything
+// buffered as a single engine.io frame, keeping one frame per packet on
+// the wire instead of one per Write call.
+type frameReadWriter struct {
+	conn frameConn
+	cur  io.ReadCloser
+
+	buf bytes.Buffer
+}
+
+func (f *frameReadWriter) Write(p []byte) (int, error) {
+	return f.buf.Write(p)
+}
+
+// Flush sends everything buffered since the last Flush as a single
+// engine.io frame. Callers that wrap a parser.Encoder around this writer
+// must call Flush once after each Encode returns.
+func (f *frameReadWriter) Flush() error {
+	if f.buf.Len() == 0 {
+		return nil
+	}
+
+	w, err := f.conn.NextWriter(frame.Binary)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	_, err = w.Write(f.buf.Bytes())
+	f.buf.Reset()
+
+	return err
+}
+
+func (f *frameReadWriter) Read(p []byte) (int, error) {
+	for {
+		if f.cur == nil {
+			_, r, err := f.conn.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			f.cur = r
+		}
+
+		n, err := f.cur.Read(p)
+		if err == io.EOF {
+			f.cur.Close()
+			f.cur = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+
+		return n, err
+	}
+}
+
+// flushingEncoder wraps a parser.Encoder built over a frameReadWriter so
+// every Encode call ends with exactly one frame on the wire, regardless
+// of how many individual Write calls the codec made to produce it.
+type flushingEncoder struct {
+	enc parser.Encoder
+	rw  *frameReadWriter
+}
+
+func (e *flushingEncoder) Encode(header parser.Header, args interface{}) error {
+	if err := e.enc.Encode(header, args); err != nil {
+		return err
+	}
+
+	return e.rw.Flush()
+}
+
+// installParserFactory builds the codec produced by factory and installs
+// it on conn's encoder/decoder, replacing the default JSON codec. It is a
+// no-op when factory is nil.
+func installParserFactory(c *conn, fc frameConn, factory func() parser.Codec) {
+	if factory == nil {
+		return
+	}
+
+	codec := factory()
+	if codec == nil {
+		return
+	}
+
+	rw := &frameReadWriter{conn: fc}
+	c.encoder = &flushingEncoder{enc: codec.NewEncoder(rw), rw: rw}
+	c.decoder = codec.NewDecoder(rw)
+}
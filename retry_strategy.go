@@ -3,53 +3,152 @@ package socketio
 import (
 	"math"
 	"math/rand"
+	"time"
 )
 
+// Policy selects the backoff algorithm used by RetryStrategy when computing
+// the delay before the next reconnect attempt.
+type Policy int
+
+const (
+	// PolicyExponential grows the delay exponentially and applies
+	// symmetric ("equal") jitter around it. This is the historical
+	// behavior of RetryStrategy.
+	PolicyExponential Policy = iota
+	// PolicyFullJitter applies the "full jitter" algorithm described in
+	// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/:
+	// sleep = rand() * min(cap, base * 2^attempt).
+	PolicyFullJitter
+	// PolicyDecorrelated applies "decorrelated jitter" backoff, where each
+	// delay is chosen uniformly between the base delay and three times
+	// the previous delay, capped at max.
+	PolicyDecorrelated
+)
+
+// RetryStrategy computes the delay to wait between client reconnect
+// attempts. The zero value is not usable; construct one with NewBackOff.
 type RetryStrategy struct {
-	ms       float64
-	max      float64
+	Policy Policy
+
+	ms       time.Duration
+	max      time.Duration
 	factor   float64
 	jitter   float64
-	attempts float64
+	attempts int
+
+	prev time.Duration
+	rand *rand.Rand
 }
 
+// NewBackOff returns a RetryStrategy configured from opts. If opts.rand is
+// nil, a source seeded from the current time is used; callers that need
+// deterministic tests can call SetSource on the result afterwards.
 func NewBackOff(opts RetryStrategy) *RetryStrategy {
+	prev := opts.ms
+
+	r := opts.rand
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
 	return &RetryStrategy{
+		Policy:   opts.Policy,
 		ms:       opts.ms,
 		max:      opts.max,
 		factor:   opts.factor,
 		jitter:   opts.jitter,
 		attempts: opts.attempts,
+		prev:     prev,
+		rand:     r,
 	}
 }
 
-func (b *RetryStrategy) Duration() float64 {
-	ms := b.ms * math.Pow(b.factor, b.attempts)
+// SetSource overrides the random source used to compute jitter, allowing
+// deterministic tests of the reconnect loop.
+func (b *RetryStrategy) SetSource(src rand.Source) {
+	b.rand = rand.New(src)
+}
+
+// NextBackoff returns the delay to wait before the next reconnect attempt,
+// according to b.Policy, and advances the attempt counter.
+func (b *RetryStrategy) NextBackoff() time.Duration {
+	var delay time.Duration
+
+	switch b.Policy {
+	case PolicyFullJitter:
+		delay = b.fullJitter()
+	case PolicyDecorrelated:
+		delay = b.decorrelated()
+	default:
+		delay = b.exponential()
+	}
+
 	b.attempts++
+	b.prev = delay
+
+	return delay
+}
+
+// Duration is kept for callers written against earlier versions of
+// RetryStrategy; it behaves exactly like NextBackoff.
+func (b *RetryStrategy) Duration() time.Duration {
+	return b.NextBackoff()
+}
+
+func (b *RetryStrategy) exponential() time.Duration {
+	ms := float64(b.ms) * math.Pow(b.factor, float64(b.attempts))
+	ms = math.Min(ms, float64(b.max))
 
 	if b.jitter > 0 {
-		randVal := rand.Float64()
+		randVal := b.rand.Float64()
 		deviation := math.Floor(randVal * b.jitter * ms)
-		jitterDecision := int(math.Floor(randVal*10)) & 1
-		if jitterDecision == 0 {
+		if int(math.Floor(randVal*10))&1 == 0 {
 			ms -= deviation
 		} else {
 			ms += deviation
 		}
+		if ms < 0 {
+			ms = 0
+		}
+	}
+
+	return time.Duration(math.Min(ms, float64(b.max)))
+}
+
+func (b *RetryStrategy) fullJitter() time.Duration {
+	cap := float64(b.max)
+	base := float64(b.ms) * math.Pow(2, float64(b.attempts))
+
+	return time.Duration(b.rand.Float64() * math.Min(cap, base))
+}
+
+func (b *RetryStrategy) decorrelated() time.Duration {
+	prev := b.prev
+	if prev < b.ms {
+		prev = b.ms
+	}
+
+	sleep := b.ms + time.Duration(b.rand.Float64()*float64(prev*3-b.ms))
+
+	if sleep > b.max {
+		sleep = b.max
 	}
 
-	return math.Min(ms, b.max)
+	return sleep
 }
 
+// Reset clears the attempt counter and the memory of the previous delay, as
+// if no attempt had been made yet.
 func (b *RetryStrategy) Reset() {
 	b.attempts = 0
+	b.prev = b.ms
 }
 
-func (b *RetryStrategy) SetMin(ms float64) {
+func (b *RetryStrategy) SetMin(ms time.Duration) {
 	b.ms = ms
 }
 
-func (b *RetryStrategy) SetMax(max float64) {
+func (b *RetryStrategy) SetMax(max time.Duration) {
 	b.max = max
 }
 func (b *RetryStrategy) SetJitter(jitter float64) {
@@ -0,0 +1,93 @@
+package socketio
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRetryStrategyNextBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   time.Duration
+	}{
+		{
+			name:   "exponential",
+			policy: PolicyExponential,
+			want:   697669857 * time.Nanosecond,
+		},
+		{
+			name:   "full jitter",
+			policy: PolicyFullJitter,
+			want:   604660287 * time.Nanosecond,
+		},
+		{
+			name:   "decorrelated",
+			policy: PolicyDecorrelated,
+			want:   2209320575 * time.Nanosecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBackOff(RetryStrategy{
+				Policy: tt.policy,
+				ms:     1 * time.Second,
+				max:    10 * time.Second,
+				factor: 2,
+				jitter: 0.5,
+			})
+			b.SetSource(rand.NewSource(1))
+
+			if got := b.NextBackoff(); got != tt.want {
+				t.Fatalf("NextBackoff() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryStrategyNextBackoffCapsAtMax(t *testing.T) {
+	for _, policy := range []Policy{PolicyExponential, PolicyFullJitter, PolicyDecorrelated} {
+		b := NewBackOff(RetryStrategy{
+			Policy: policy,
+			ms:     1 * time.Second,
+			max:    2 * time.Second,
+			factor: 2,
+			jitter: 2, // jitter > 1 on the old equal-jitter scheme could
+			// send the delay negative or over max before it was clamped.
+		})
+		b.SetSource(rand.NewSource(2))
+
+		for attempt := 0; attempt < 10; attempt++ {
+			if got := b.NextBackoff(); got < 0 || got > b.max {
+				t.Fatalf("policy %d attempt %d: NextBackoff() = %s, want within [0, %s]", policy, attempt, got, b.max)
+			}
+		}
+	}
+}
+
+func TestRetryStrategyReset(t *testing.T) {
+	b := NewBackOff(RetryStrategy{
+		Policy: PolicyExponential,
+		ms:     1 * time.Second,
+		max:    10 * time.Second,
+		factor: 2,
+		jitter: 0,
+	})
+	b.SetSource(rand.NewSource(3))
+
+	b.NextBackoff()
+	b.NextBackoff()
+	if b.attempts == 0 {
+		t.Fatalf("attempts = 0 after NextBackoff, want > 0")
+	}
+
+	b.Reset()
+	if b.attempts != 0 {
+		t.Fatalf("attempts = %d after Reset, want 0", b.attempts)
+	}
+	if b.prev != b.ms {
+		t.Fatalf("prev = %s after Reset, want %s", b.prev, b.ms)
+	}
+}
@@ -0,0 +1,62 @@
+package socketio
+
+// ringBuffer is a fixed-capacity circular byte buffer used to hold bytes
+// received for a Stream between Reads. It grows past capacity rather than
+// dropping bytes; well-behaved peers never exceed the advertised receive
+// window, so growth should only happen under flow-control violations.
+type ringBuffer struct {
+	buf        []byte
+	head, tail int
+	size       int
+}
+
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{buf: make([]byte, capacity)}
+}
+
+func (r *ringBuffer) Len() int {
+	return r.size
+}
+
+// Write appends p to the buffer, growing it if there isn't enough room.
+func (r *ringBuffer) Write(p []byte) int {
+	if len(p) > len(r.buf)-r.size {
+		r.grow(r.size + len(p))
+	}
+
+	for _, b := range p {
+		r.buf[r.tail] = b
+		r.tail = (r.tail + 1) % len(r.buf)
+		r.size++
+	}
+
+	return len(p)
+}
+
+// Read copies up to len(p) bytes out of the buffer, returning the number
+// of bytes copied.
+func (r *ringBuffer) Read(p []byte) int {
+	n := len(p)
+	if n > r.size {
+		n = r.size
+	}
+
+	for i := 0; i < n; i++ {
+		p[i] = r.buf[r.head]
+		r.head = (r.head + 1) % len(r.buf)
+	}
+	r.size -= n
+
+	return n
+}
+
+func (r *ringBuffer) grow(capacity int) {
+	oldSize := r.size
+	newBuf := make([]byte, capacity)
+	r.Read(newBuf[:oldSize])
+
+	r.buf = newBuf
+	r.head = 0
+	r.tail = oldSize
+	r.size = oldSize
+}
@@ -0,0 +1,82 @@
+package socketio
+
+import "testing"
+
+func TestRingBufferWriteReadWraparound(t *testing.T) {
+	r := newRingBuffer(4)
+
+	if n := r.Write([]byte{1, 2, 3}); n != 3 {
+		t.Fatalf("Write() = %d, want 3", n)
+	}
+
+	buf := make([]byte, 2)
+	if n := r.Read(buf); n != 2 || buf[0] != 1 || buf[1] != 2 {
+		t.Fatalf("Read() = %d, %v, want 2, [1 2]", n, buf)
+	}
+
+	// head is now at index 2 with 1 byte (the 3) still buffered; writing 3
+	// more bytes wraps tail around past the end of the backing array
+	// without growing, since there's room once the drained bytes are
+	// accounted for.
+	if n := r.Write([]byte{4, 5, 6}); n != 3 {
+		t.Fatalf("Write() = %d, want 3", n)
+	}
+
+	if got := r.Len(); got != 4 {
+		t.Fatalf("Len() = %d, want 4", got)
+	}
+
+	out := make([]byte, 4)
+	if n := r.Read(out); n != 4 {
+		t.Fatalf("Read() = %d, want 4", n)
+	}
+	want := []byte{3, 4, 5, 6}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Fatalf("Read() = %v, want %v", out, want)
+		}
+	}
+}
+
+func TestRingBufferGrowsPastCapacity(t *testing.T) {
+	r := newRingBuffer(2)
+
+	if n := r.Write([]byte{1, 2}); n != 2 {
+		t.Fatalf("Write() = %d, want 2", n)
+	}
+	if n := r.Write([]byte{3, 4, 5}); n != 3 {
+		t.Fatalf("Write() = %d, want 3", n)
+	}
+
+	if got, want := r.Len(), 5; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	if got := len(r.buf); got < 5 {
+		t.Fatalf("grow left capacity at %d, want >= 5", got)
+	}
+
+	out := make([]byte, 5)
+	if n := r.Read(out); n != 5 {
+		t.Fatalf("Read() = %d, want 5", n)
+	}
+	want := []byte{1, 2, 3, 4, 5}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Fatalf("Read() = %v, want %v", out, want)
+		}
+	}
+}
+
+func TestRingBufferReadMoreThanAvailable(t *testing.T) {
+	r := newRingBuffer(4)
+	r.Write([]byte{1, 2})
+
+	out := make([]byte, 4)
+	n := r.Read(out)
+	if n != 2 {
+		t.Fatalf("Read() = %d, want 2", n)
+	}
+	if r.Len() != 0 {
+		t.Fatalf("Len() = %d after draining, want 0", r.Len())
+	}
+}
@@ -0,0 +1,133 @@
+package socketio
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/somprabhsharma/go-socket.io/logger"
+)
+
+// Server tracks room membership for its local connections and, once a
+// Broker is attached via SetBroker, fans every local room broadcast out
+// to the other nodes subscribed to that Broker and applies whatever they
+// publish back onto this node's local members.
+type Server struct {
+	lock  sync.Mutex
+	rooms map[string]map[string]map[string]Conn // namespace -> room -> conn id -> Conn
+
+	broker Broker
+}
+
+// NewServer returns a Server with no Broker attached; local-only
+// broadcasting works without ever calling SetBroker.
+func NewServer() *Server {
+	return &Server{
+		rooms: make(map[string]map[string]map[string]Conn),
+	}
+}
+
+// SetBroker attaches b as this Server's cross-node Broker and subscribes
+// to it immediately, so broadcasts published by other nodes are applied
+// to this node's local room members. Publishing from this node happens
+// as part of BroadcastToRoom, not here.
+func (s *Server) SetBroker(b Broker) error {
+	s.lock.Lock()
+	s.broker = b
+	s.lock.Unlock()
+
+	return b.Subscribe(func(namespace, room, event string, payload []byte) {
+		var args []interface{}
+		if err := json.Unmarshal(payload, &args); err != nil {
+			logger.Error("server: decode broker payload:", err)
+			return
+		}
+
+		s.emitLocal(namespace, room, event, args)
+	})
+}
+
+// Join registers conn as a member of room in namespace and, if the
+// attached Broker implements RoomTracker, tells it so other nodes know
+// this node has a member in the room.
+func (s *Server) Join(namespace, room string, conn Conn) error {
+	s.lock.Lock()
+	byRoom, ok := s.rooms[namespace]
+	if !ok {
+		byRoom = make(map[string]map[string]Conn)
+		s.rooms[namespace] = byRoom
+	}
+	members, ok := byRoom[room]
+	if !ok {
+		members = make(map[string]Conn)
+		byRoom[room] = members
+	}
+	members[conn.ID()] = conn
+	broker := s.broker
+	s.lock.Unlock()
+
+	tracker, ok := broker.(RoomTracker)
+	if !ok {
+		return nil
+	}
+
+	return tracker.Join(namespace, room, conn.ID())
+}
+
+// Leave removes conn from room in namespace and, if the attached Broker
+// implements RoomTracker, tells it so.
+func (s *Server) Leave(namespace, room string, conn Conn) error {
+	s.lock.Lock()
+	if byRoom, ok := s.rooms[namespace]; ok {
+		if members, ok := byRoom[room]; ok {
+			delete(members, conn.ID())
+		}
+	}
+	broker := s.broker
+	s.lock.Unlock()
+
+	tracker, ok := broker.(RoomTracker)
+	if !ok {
+		return nil
+	}
+
+	return tracker.Leave(namespace, room, conn.ID())
+}
+
+// BroadcastToRoom emits event to every local member of room in namespace
+// and, if a Broker is attached, publishes it so every other node does
+// the same for its own local members.
+func (s *Server) BroadcastToRoom(namespace, room, event string, args ...interface{}) error {
+	s.emitLocal(namespace, room, event, args)
+
+	s.lock.Lock()
+	broker := s.broker
+	s.lock.Unlock()
+	if broker == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	return broker.Publish(namespace, room, event, payload)
+}
+
+func (s *Server) emitLocal(namespace, room, event string, args []interface{}) {
+	s.lock.Lock()
+	var members []Conn
+	if byRoom, ok := s.rooms[namespace]; ok {
+		if m, ok := byRoom[room]; ok {
+			members = make([]Conn, 0, len(m))
+			for _, c := range m {
+				members = append(members, c)
+			}
+		}
+	}
+	s.lock.Unlock()
+
+	for _, c := range members {
+		c.Emit(event, args...)
+	}
+}
@@ -0,0 +1,351 @@
+package socketio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/somprabhsharma/go-socket.io/logger"
+	"github.com/somprabhsharma/go-socket.io/parser"
+)
+
+// defaultStreamWindow is the receive window, in bytes, that both peers
+// assume for a newly opened Stream; it is not sent in StreamOpen. It bounds
+// how much unacknowledged data the peer may have in flight for one stream.
+const defaultStreamWindow = 256 * 1024
+
+// recvWindowUpdateThreshold is how many bytes Read must drain from recvBuf
+// before it credits them back to the peer with a WindowUpdate. Without a
+// threshold, every single Read call would need its own control packet;
+// batching keeps that overhead proportional to throughput instead.
+const recvWindowUpdateThreshold = defaultStreamWindow / 4
+
+// ErrStreamClosed is returned by Read/Write once the stream has been
+// closed locally or by the peer.
+var ErrStreamClosed = errors.New("socketio: stream closed")
+
+// streamPayload is the wire payload carried by StreamOpen/StreamData/
+// StreamClose packets, encoded through the same Encode(header, args) path
+// used for events and acks.
+type streamPayload struct {
+	Name         string `json:"name,omitempty"`
+	Data         []byte `json:"data,omitempty"`
+	WindowUpdate uint32 `json:"windowUpdate,omitempty"`
+}
+
+// Stream is an ordered, flow-controlled byte stream multiplexed over a
+// single engine.io connection alongside ordinary event/ack packets. It
+// implements io.ReadWriteCloser.
+type Stream struct {
+	id   uint32
+	name string
+	conn *conn
+
+	// writeLock is the owning Client's writeLock, shared so stream writes
+	// can't interleave with clientWrite's own Encode call or other
+	// direct encoder users such as EmitWithAck.
+	writeLock *sync.Mutex
+
+	sendLock   sync.Mutex
+	sendWindow uint32
+	windowFree chan struct{}
+
+	recvLock  sync.Mutex
+	recvBuf   *ringBuffer
+	recvCond  *sync.Cond
+	recvFreed uint32 // bytes drained from recvBuf not yet credited back to the peer
+
+	closed     chan struct{}
+	closeOnce  sync.Once
+	remoteDone int32
+}
+
+func newStream(id uint32, c *conn, name string, writeLock *sync.Mutex) *Stream {
+	s := &Stream{
+		id:         id,
+		name:       name,
+		conn:       c,
+		writeLock:  writeLock,
+		sendWindow: defaultStreamWindow,
+		windowFree: make(chan struct{}, 1),
+		recvBuf:    newRingBuffer(defaultStreamWindow),
+		closed:     make(chan struct{}),
+	}
+	s.recvCond = sync.NewCond(&s.recvLock)
+
+	return s
+}
+
+// OpenStream opens a new multiplexed Stream named name over the client's
+// connection and blocks until the peer acknowledges it, ctx is done, or
+// sending the open fails. The returned Stream can be used immediately
+// once OpenStream returns.
+func (c *Client) OpenStream(ctx context.Context, name string) (*Stream, error) {
+	if c.conn == nil {
+		return nil, errors.New("socketio: client not connected")
+	}
+
+	id := atomic.AddUint32(&c.streamID, 1)
+	s := newStream(id, c.conn, name, &c.writeLock)
+	c.streams.Store(id, s)
+
+	ack := make(chan struct{})
+	c.pendingStreamOpens.Store(id, ack)
+	defer c.pendingStreamOpens.Delete(id)
+
+	header := parser.Header{
+		Type:      parser.StreamOpen,
+		Namespace: c.namespace,
+		ID:        uint64(id),
+		NeedAck:   true,
+	}
+
+	c.writeLock.Lock()
+	err := c.conn.encoder.Encode(header, streamPayload{Name: name})
+	c.writeLock.Unlock()
+	if err != nil {
+		c.streams.Delete(id)
+		return nil, err
+	}
+
+	select {
+	case <-ack:
+		return s, nil
+	case <-ctx.Done():
+		c.streams.Delete(id)
+		return nil, ctx.Err()
+	}
+}
+
+// AcceptStream blocks until the peer opens a new Stream toward us or ctx
+// is done; closing the client does not unblock it. It is the receiving
+// counterpart to OpenStream: whichever side didn't call OpenStream for a
+// given Stream must call AcceptStream to observe it.
+//
+// Only the Client side has this method; Conn has no AcceptStream.
+func (c *Client) AcceptStream(ctx context.Context) (*Stream, error) {
+	select {
+	case s := <-c.streamOpens:
+		return s, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// handleStreamPacket routes an incoming StreamOpen/StreamData/StreamClose
+// packet to the Stream it targets, looked up by the id carried in the
+// packet header.
+func (c *Client) handleStreamPacket(header parser.Header) error {
+	var payload streamPayload
+	if err := c.conn.decoder.DecodeArgs(&payload); err != nil {
+		return err
+	}
+
+	id := uint32(header.ID)
+
+	switch header.Type {
+	case parser.StreamOpen:
+		if header.NeedAck {
+			// The peer is opening a new stream toward us: create it, hand
+			// it to AcceptStream, then ack so their OpenStream returns.
+			if _, ok := c.streams.Load(id); !ok {
+				s := newStream(id, c.conn, payload.Name, &c.writeLock)
+				c.streams.Store(id, s)
+
+				select {
+				case c.streamOpens <- s:
+				default:
+					// Backlog full: the peer will see writes stall the
+					// same way they would against any unread stream.
+				}
+			}
+
+			ackHeader := parser.Header{
+				Type:      parser.StreamOpen,
+				Namespace: header.Namespace,
+				ID:        header.ID,
+			}
+			c.writeLock.Lock()
+			err := c.conn.encoder.Encode(ackHeader, streamPayload{})
+			c.writeLock.Unlock()
+			return err
+		}
+
+		// This is the ack for a stream we opened ourselves.
+		if v, ok := c.pendingStreamOpens.Load(id); ok {
+			close(v.(chan struct{}))
+		}
+		return nil
+	case parser.StreamClose:
+		if v, ok := c.streams.Load(id); ok {
+			v.(*Stream).closeLocal()
+			c.streams.Delete(id)
+		}
+		return nil
+	case parser.StreamData:
+		v, ok := c.streams.Load(id)
+		if !ok {
+			return nil
+		}
+		s := v.(*Stream)
+
+		if payload.WindowUpdate > 0 {
+			s.grantWindow(payload.WindowUpdate)
+		}
+		if len(payload.Data) > 0 {
+			s.recvLock.Lock()
+			s.recvBuf.Write(payload.Data)
+			s.recvCond.Broadcast()
+			s.recvLock.Unlock()
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// Write sends p as one or more StreamData packets, none larger than the
+// peer's remaining receive window, blocking whenever that window is
+// exhausted until the peer grants more.
+func (s *Stream) Write(p []byte) (int, error) {
+	s.sendLock.Lock()
+	defer s.sendLock.Unlock()
+
+	written := 0
+	for written < len(p) {
+		select {
+		case <-s.closed:
+			return written, ErrStreamClosed
+		default:
+		}
+
+		if atomic.LoadUint32(&s.sendWindow) == 0 {
+			<-s.windowFree
+			continue
+		}
+
+		chunk := p[written:]
+		if uint32(len(chunk)) > atomic.LoadUint32(&s.sendWindow) {
+			chunk = chunk[:atomic.LoadUint32(&s.sendWindow)]
+		}
+
+		header := parser.Header{
+			Type:      parser.StreamData,
+			Namespace: "",
+			ID:        uint64(s.id),
+		}
+
+		s.writeLock.Lock()
+		err := s.conn.encoder.Encode(header, streamPayload{Data: chunk})
+		s.writeLock.Unlock()
+		if err != nil {
+			return written, err
+		}
+
+		atomic.AddUint32(&s.sendWindow, -uint32(len(chunk)))
+		written += len(chunk)
+	}
+
+	return written, nil
+}
+
+// grantWindow credits n bytes to the send window, unblocking any Write
+// waiting for room.
+func (s *Stream) grantWindow(n uint32) {
+	atomic.AddUint32(&s.sendWindow, n)
+
+	select {
+	case s.windowFree <- struct{}{}:
+	default:
+	}
+}
+
+// Read drains bytes received for this stream, blocking until at least one
+// byte is available, the stream closes, or the peer closes its side.
+func (s *Stream) Read(p []byte) (int, error) {
+	s.recvLock.Lock()
+	for s.recvBuf.Len() == 0 {
+		if atomic.LoadInt32(&s.remoteDone) != 0 {
+			s.recvLock.Unlock()
+			return 0, io.EOF
+		}
+		select {
+		case <-s.closed:
+			s.recvLock.Unlock()
+			return 0, ErrStreamClosed
+		default:
+		}
+		s.recvCond.Wait()
+	}
+	n := s.recvBuf.Read(p)
+
+	s.recvFreed += uint32(n)
+	update := uint32(0)
+	if s.recvFreed >= recvWindowUpdateThreshold {
+		update = s.recvFreed
+		s.recvFreed = 0
+	}
+	s.recvLock.Unlock()
+
+	if update > 0 {
+		s.sendWindowUpdate(update)
+	}
+
+	return n, nil
+}
+
+// sendWindowUpdate credits n bytes back to the peer's send window,
+// piggy-backed on a data-less StreamData packet, once Read has drained
+// enough of recvBuf to be worth announcing.
+func (s *Stream) sendWindowUpdate(n uint32) {
+	header := parser.Header{
+		Type: parser.StreamData,
+		ID:   uint64(s.id),
+	}
+
+	s.writeLock.Lock()
+	err := s.conn.encoder.Encode(header, streamPayload{WindowUpdate: n})
+	s.writeLock.Unlock()
+	if err != nil {
+		logger.Error("stream window update:", err)
+	}
+}
+
+// Close closes the stream locally and notifies the peer so it can release
+// its own half, piggy-backed as a StreamClose packet. Any Read or Write
+// currently blocked on this stream is woken up and returns ErrStreamClosed.
+func (s *Stream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.closed)
+
+		s.recvLock.Lock()
+		s.recvCond.Broadcast()
+		s.recvLock.Unlock()
+
+		select {
+		case s.windowFree <- struct{}{}:
+		default:
+		}
+
+		header := parser.Header{
+			Type: parser.StreamClose,
+			ID:   uint64(s.id),
+		}
+
+		s.writeLock.Lock()
+		err = s.conn.encoder.Encode(header, streamPayload{})
+		s.writeLock.Unlock()
+	})
+
+	return err
+}
+
+func (s *Stream) closeLocal() {
+	atomic.StoreInt32(&s.remoteDone, 1)
+	s.recvLock.Lock()
+	s.recvCond.Broadcast()
+	s.recvLock.Unlock()
+}